@@ -1,9 +1,12 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,6 +14,34 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+var (
+	dumpStartTime   string
+	dumpEndTime     string
+	dumpStartPos    int64
+	dumpEndPos      int64
+	dumpCommands    string
+	dumpOnlyInvalid bool
+	dumpFollow      bool
+)
+
+func dumpCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:  "dump FILE",
+		Args: cobra.ExactArgs(1),
+		RunE: dump,
+	}
+
+	cmd.Flags().StringVar(&dumpStartTime, "start-time", "", "Only show frames at or after this time (RFC3339, or a duration from the first frame)")
+	cmd.Flags().StringVar(&dumpEndTime, "end-time", "", "Only show frames at or before this time (RFC3339, or a duration from the first frame)")
+	cmd.Flags().Int64Var(&dumpStartPos, "start-pos", 0, "Start reading at this byte offset into FILE")
+	cmd.Flags().Int64Var(&dumpEndPos, "end-pos", 0, "Stop reading at this byte offset into FILE (0 for end of file)")
+	cmd.Flags().StringVar(&dumpCommands, "command", "", "Only show these comma-separated command bytes, e.g. 0x19,0x07")
+	cmd.Flags().BoolVar(&dumpOnlyInvalid, "only-invalid-crc", false, "Only show frames with an invalid CRC")
+	cmd.Flags().BoolVar(&dumpFollow, "follow", false, "Keep reading FILE as a concurrent sniff appends to it")
+
+	return &cmd
+}
+
 func dump(_ *cobra.Command, args []string) error {
 	f, err := os.Open(args[0])
 	if err != nil {
@@ -18,42 +49,162 @@ func dump(_ *cobra.Command, args []string) error {
 	}
 	defer f.Close()
 
+	if dumpStartPos > 0 {
+		if _, err := f.Seek(dumpStartPos, io.SeekStart); err != nil {
+			return fmt.Errorf("seeking to start-pos: %w", err)
+		}
+	}
+
+	var r io.Reader = f
+	if dumpEndPos > 0 && !dumpFollow {
+		r = io.LimitReader(f, dumpEndPos-dumpStartPos)
+	}
+
+	predicate, err := framePredicate(dumpStartTime, dumpEndTime, dumpCommands, dumpOnlyInvalid)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
 	msgs := make(chan galaxy.Message, 100)
+	dec := galaxy.NewDecoder(nil)
+	frames := dec.Decode(ctx, msgs)
 
 	var g errgroup.Group
-	g.Go(func() error { return processMsgs(msgs) })
-	g.Go(func() error { return galaxy.ReadIn(msgs, f) })
+	g.Go(func() error { return processFrames(frames, dec.Registry, predicate) })
+	if dumpFollow {
+		g.Go(func() error { return galaxy.FollowIn(ctx, msgs, r) })
+	} else {
+		g.Go(func() error { return galaxy.ReadIn(ctx, msgs, r) })
+	}
 
 	return g.Wait()
 }
 
-func processMsgs(msgs <-chan galaxy.Message) error {
-	var lastMsg, lastRead time.Time
-	var thisMsg bytes.Buffer
-
-	for msg := range msgs {
-		if !lastMsg.IsZero() && msg.Timestamp.Sub(lastRead) > 5*time.Millisecond {
-			bs := thisMsg.Bytes()
-			fmt.Printf("%s: %X %s\n", lastMsg.Format("15:04:05.000"), bs, render(bs))
-			thisMsg.Reset()
-			lastMsg = msg.Timestamp
+func processFrames(frames <-chan galaxy.Frame, reg *galaxy.CommandRegistry, keep func(galaxy.Frame) bool) error {
+	for f := range frames {
+		if !keep(f) {
+			continue
 		}
 
-		lastRead = msg.Timestamp
-		if lastMsg.IsZero() {
-			lastMsg = lastRead
+		status := "OK"
+		if !f.Valid {
+			status = "INVALID CRC"
 		}
 
-		_, err := thisMsg.Write(msg.Data)
-		if err != nil {
-			return err
-		}
+		fmt.Printf("%s: %02X>%02X %s [%s]\n",
+			f.Timestamp.Format("15:04:05.000"), f.Address, f.CRC,
+			reg.Describe(f.Command, f.Payload), status)
 	}
 
 	return nil
 }
 
-func render(bs []byte) string {
-	pad := fmt.Sprintf("%*s", 20-(len(bs)*2), " ")
-	return fmt.Sprintf("%s%s", pad, bs)
+// framePredicate composes the dump command's filter flags into a single
+// predicate over decoded Frames.
+func framePredicate(startRaw, endRaw, commandsRaw string, onlyInvalid bool) (func(galaxy.Frame) bool, error) {
+	timeOK, err := timePredicate(startRaw, endRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	commandOK, err := commandPredicate(commandsRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(f galaxy.Frame) bool {
+		if onlyInvalid && f.Valid {
+			return false
+		}
+
+		return timeOK(f) && commandOK(f)
+	}, nil
+}
+
+// timeBound is a --start-time/--end-time value: either an absolute time,
+// or a duration measured from the first frame seen.
+type timeBound struct {
+	set        bool
+	absolute   time.Time
+	relative   time.Duration
+	isRelative bool
+}
+
+func parseTimeBound(s string) (timeBound, error) {
+	if s == "" {
+		return timeBound{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return timeBound{set: true, absolute: t}, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return timeBound{set: true, relative: d, isRelative: true}, nil
+	}
+
+	return timeBound{}, fmt.Errorf("invalid time %q: want RFC3339 or a duration", s)
+}
+
+func (b timeBound) resolve(base time.Time) time.Time {
+	if b.isRelative {
+		return base.Add(b.relative)
+	}
+	return b.absolute
+}
+
+func timePredicate(startRaw, endRaw string) (func(galaxy.Frame) bool, error) {
+	start, err := parseTimeBound(startRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseTimeBound(endRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	if !start.set && !end.set {
+		return func(galaxy.Frame) bool { return true }, nil
+	}
+
+	var base time.Time
+
+	return func(f galaxy.Frame) bool {
+		if base.IsZero() {
+			base = f.Timestamp
+		}
+
+		if start.set && f.Timestamp.Before(start.resolve(base)) {
+			return false
+		}
+
+		if end.set && f.Timestamp.After(end.resolve(base)) {
+			return false
+		}
+
+		return true
+	}, nil
+}
+
+func commandPredicate(spec string) (func(galaxy.Frame) bool, error) {
+	if spec == "" {
+		return func(galaxy.Frame) bool { return true }, nil
+	}
+
+	want := make(map[galaxy.Command]struct{})
+	for _, s := range strings.Split(spec, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(s), 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command %q: %w", s, err)
+		}
+
+		want[galaxy.Command(v)] = struct{}{}
+	}
+
+	return func(f galaxy.Frame) bool {
+		_, ok := want[f.Command]
+		return ok
+	}, nil
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.tigermatt.uk/galaxy/emitter"
+)
+
+// buildFanout parses the repeatable --emit targets (each possibly a
+// comma-separated list) into a single emitter.Fanout. It returns nil if
+// no targets were given.
+func buildFanout(ctx context.Context, targets []string) (*emitter.Fanout, error) {
+	var sinks []emitter.Sink
+
+	for _, group := range targets {
+		for _, target := range strings.Split(group, ",") {
+			target = strings.TrimSpace(target)
+			if target == "" {
+				continue
+			}
+
+			sink, err := buildSink(ctx, target)
+			if err != nil {
+				return nil, fmt.Errorf("parsing --emit target %q: %w", target, err)
+			}
+
+			sinks = append(sinks, sink)
+		}
+	}
+
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+
+	return emitter.New(sinks...), nil
+}
+
+func buildSink(ctx context.Context, target string) (emitter.Sink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "jsonl":
+		if u.Host == "stdout" || target == "jsonl://stdout" {
+			return emitter.NewJSONLinesSink(os.Stdout), nil
+		}
+
+		return emitter.NewJSONLinesFileSink(u.Host + u.Path)
+	case "mqtt":
+		topicRoot := strings.TrimPrefix(u.Path, "/")
+		if topicRoot == "" {
+			topicRoot = "galaxy"
+		}
+
+		return emitter.NewMQTTSink("tcp://"+u.Host, topicRoot)
+	case "otlp":
+		return emitter.NewOTLPSink(ctx, u.Host)
+	default:
+		return nil, fmt.Errorf("unknown emitter scheme %q", u.Scheme)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"go.tigermatt.uk/galaxy"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	emulateLine0 = "Hello World"
+	emulateLine1 = "Testing 123"
+	emulateHTTP  = ""
+)
+
+func emulateCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "emulate"}
+
+	keypad := &cobra.Command{
+		Use:  "keypad DEVICE",
+		Args: cobra.ExactArgs(1),
+		RunE: emulateKeypad,
+	}
+	keypad.Flags().StringVar(&emulateLine0, "line0", emulateLine0, "Initial text for screen row 0")
+	keypad.Flags().StringVar(&emulateLine1, "line1", emulateLine1, "Initial text for screen row 1")
+	keypad.Flags().StringVar(&emulateHTTP, "http", emulateHTTP, "Address to serve the keypad's HTTP control endpoint on, e.g. :8080")
+
+	cmd.AddCommand(keypad)
+
+	return cmd
+}
+
+func emulateKeypad(_ *cobra.Command, args []string) error {
+	ctx := listenStop()
+
+	port, err := openSerial(args[0])
+	if err != nil {
+		return fmt.Errorf("opening serial port %s: %w", args[0], err)
+	}
+	defer port.Close()
+
+	k := galaxy.NewKeypad(emulateLine0, emulateLine1)
+
+	var g errgroup.Group
+	g.Go(func() error { return k.Serve(ctx, port) })
+
+	if emulateHTTP != "" {
+		g.Go(func() error { return serveKeypadControl(ctx, emulateHTTP, k) })
+	}
+
+	return g.Wait()
+}
+
+type keyRequest struct {
+	Key string `json:"key"`
+}
+
+type screenRequest struct {
+	Line0  string `json:"line0"`
+	Line1  string `json:"line1"`
+	Tamper bool   `json:"tamper"`
+}
+
+// serveKeypadControl serves a small HTTP API so tests and demos can
+// drive an emulated Keypad remotely: POST /key to press a key, PUT
+// /screen to set the display text and tamper state.
+func serveKeypadControl(ctx context.Context, addr string, k *galaxy.Keypad) error {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /key", func(w http.ResponseWriter, r *http.Request) {
+		var req keyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Key) != 1 {
+			http.Error(w, `expected JSON body {"key": "<single char>"}`, http.StatusBadRequest)
+			return
+		}
+
+		k.PressKey(rune(req.Key[0]))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("PUT /screen", func(w http.ResponseWriter, r *http.Request) {
+		var req screenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, `expected JSON body {"line0": ..., "line1": ..., "tamper": ...}`, http.StatusBadRequest)
+			return
+		}
+
+		k.SetLine(0, req.Line0)
+		k.SetLine(1, req.Line1)
+		k.SetTamper(req.Tamper)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
@@ -14,11 +14,9 @@ func main() {
 	}
 
 	cmd.AddCommand(sniffCommand())
-	cmd.AddCommand(&cobra.Command{
-		Use:  "dump FILE",
-		Args: cobra.ExactArgs(1),
-		RunE: dump,
-	})
+	cmd.AddCommand(dumpCommand())
+	cmd.AddCommand(replayCommand())
+	cmd.AddCommand(emulateCommand())
 
 	if err := cmd.Execute(); err != nil {
 		log.Fatalln(err)
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.bug.st/serial"
+	"go.tigermatt.uk/galaxy"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	replaySpeed = 1.0
+	replayLoop  = false
+)
+
+func replayCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:  "replay FILE DEVICE",
+		Args: cobra.ExactArgs(2),
+		RunE: replay,
+	}
+	cmd.Flags().Float64Var(&replaySpeed, "speed", replaySpeed, "Playback speed multiplier, e.g. 2.0 for 2x")
+	cmd.Flags().BoolVar(&replayLoop, "loop", replayLoop, "Loop the capture until interrupted")
+
+	return &cmd
+}
+
+// replay plays a capture back onto a serial port, preserving the
+// original inter-message timing from Message.Timestamp. It is the
+// counterpart to sniff/dump: recorded traffic can be fed back through
+// the decoder or a keypad emulator without the physical panel attached.
+func replay(_ *cobra.Command, args []string) error {
+	if replaySpeed <= 0 {
+		return fmt.Errorf("--speed must be positive")
+	}
+
+	ctx := listenStop()
+
+	port, err := openSerial(args[1])
+	if err != nil {
+		return fmt.Errorf("opening serial port %s: %w", args[1], err)
+	}
+	defer port.Close()
+
+	for {
+		if err := replayOnce(ctx, args[0], port); err != nil {
+			return err
+		}
+
+		if !replayLoop {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
+func replayOnce(ctx context.Context, path string, port serial.Port) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening capture: %w", err)
+	}
+	defer f.Close()
+
+	msgs := make(chan galaxy.Message, 100)
+
+	var g errgroup.Group
+	g.Go(func() error { return galaxy.ReadIn(ctx, msgs, f) })
+	g.Go(func() error { return writeMsgs(ctx, port, msgs) })
+
+	return g.Wait()
+}
+
+// writeMsgs writes each message's bytes to port, waiting between writes
+// for the same gap (scaled by --speed) that separated them when
+// recorded.
+func writeMsgs(ctx context.Context, port serial.Port, msgs <-chan galaxy.Message) error {
+	var last time.Time
+
+	for msg := range msgs {
+		if !last.IsZero() {
+			if gap := msg.Timestamp.Sub(last); gap > 0 {
+				select {
+				case <-time.After(time.Duration(float64(gap) / replaySpeed)):
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+		last = msg.Timestamp
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if _, err := port.Write(msg.Data); err != nil {
+			return fmt.Errorf("writing to serial port: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
@@ -9,12 +8,15 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"go.bug.st/serial"
+	"go.tigermatt.uk/galaxy"
+	"go.tigermatt.uk/galaxy/emitter"
 )
 
 var (
-	dumpAllReads    = false
 	interMessageGap = 10 * time.Millisecond
-	slaveReplyGap   = interMessageGap
+	captureFormat   = "gob"
+	emitTargets     []string
 )
 
 func sniffCommand() *cobra.Command {
@@ -24,12 +26,25 @@ func sniffCommand() *cobra.Command {
 		RunE: sniff,
 	}
 	cmd.Flags().DurationVar(&interMessageGap, "intermessage-gap", interMessageGap, "Gap between messages")
-	cmd.Flags().BoolVar(&dumpAllReads, "dump-reads", dumpAllReads, "Dump all read operations")
-	cmd.Flags().DurationVar(&slaveReplyGap, "slave-gap", slaveReplyGap, "Slave reply time")
+	cmd.Flags().StringVar(&captureFormat, "format", captureFormat, "Capture format to record in: gob, jsonl or pcapng")
+	cmd.Flags().StringArrayVar(&emitTargets, "emit", nil, "Fan out decoded frames to a sink, e.g. jsonl://stdout, mqtt://host:1883/topic, otlp://host:4317 (repeatable)")
 
 	return &cmd
 }
 
+func formatByName(name string) (galaxy.Format, error) {
+	switch name {
+	case "gob":
+		return galaxy.GobFormat{}, nil
+	case "jsonl":
+		return galaxy.JSONLinesFormat{}, nil
+	case "pcapng":
+		return galaxy.PCAPNGFormat{}, nil
+	default:
+		return nil, fmt.Errorf("unknown capture format %q", name)
+	}
+}
+
 func listenStop() context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -48,70 +63,90 @@ func outFilename() string {
 	return fmt.Sprintf("%d.dat", time.Now().UTC().Unix())
 }
 
+func openSerial(device string) (serial.Port, error) {
+	return serial.Open(device, &serial.Mode{
+		BaudRate: 9600,
+		DataBits: 8,
+		Parity:   serial.NoParity,
+		StopBits: serial.OneStopBit,
+	})
+}
+
 func sniff(_ *cobra.Command, args []string) error {
 	ctx := listenStop()
 
-	s, err := serial.openport(&serial.config{
-		name:        "/dev/ttyusb0",
-		baud:        9600,
-		readtimeout: 500 * time.microsecond,
-	})
+	format, err := formatByName(captureFormat)
 	if err != nil {
-		return fmt.errorf("opening serial: %w", err)
+		return err
 	}
 
-	bs := make([]byte, 128)
-	var last time.Time
-	msg := time.Now()
-	var buf bytes.Buffer
+	fanout, err := buildFanout(ctx, emitTargets)
+	if err != nil {
+		return err
+	}
 
-	nextGap := interMessageGap
+	port, err := openSerial(args[0])
+	if err != nil {
+		return fmt.Errorf("opening serial port %s: %w", args[0], err)
+	}
+	defer port.Close()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		default:
-		}
+	out, err := os.Create(outFilename())
+	if err != nil {
+		return fmt.Errorf("creating capture file: %w", err)
+	}
+	defer out.Close()
 
-		n, err := s.Read(bs)
-		if err != nil {
-			return err
-		}
+	rec := &galaxy.Recorder{Dest: out, Format: format}
+	msgs := make(chan galaxy.Message, 100)
 
-		diff := time.Since(last)
-		sinceStartOfMessage := time.Since(msg)
-		last = time.Now()
+	s := galaxy.Sniffer{
+		Port: port,
+		OnReceive: func(bs []byte) {
+			msg := galaxy.Message{Data: append([]byte(nil), bs...), Timestamp: time.Now()}
 
-		if dumpAllReads {
-			if n == 0 {
-				fmt.Println(".")
+			if err := rec.Receive(msg); err != nil {
+				fmt.Fprintf(os.Stderr, "recording message: %s\n", err)
 			}
 
-			fmt.Printf("%s % 02X\n", last.Format("15:04:05.000"), bs[:n])
-		}
+			msgs <- msg
+		},
+	}
 
-		if diff > nextGap {
-			if !dumpAllReads {
-				nextGap = dumpMsg(msg, last, nextGap, sinceStartOfMessage, buf.Bytes())
-			}
-			buf.Reset()
-			msg = last
-		}
+	dec := galaxy.NewDecoder(nil)
+	dec.Gap = interMessageGap
+	frames := dec.Decode(ctx, msgs)
 
-		buf.Write(bs[:n])
-	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		printFrames(frames, dec.Registry, fanout)
+	}()
+
+	err = s.Consume(ctx)
+	close(msgs)
+	<-done
+
+	return err
 }
 
-func dumpMsg(start, end time.Time, lastGap, d time.Duration, bs []byte) (nextGap time.Duration) {
-	fmt.Printf("> %s %s (%02d gap=%02d) %+02d: % 02X\n",
-		start.Format("15:04:05.000"),
-		end.Format("15:04:05.000"),
-		len(bs), lastGap.Milliseconds(), d.Milliseconds(), bs)
+func printFrames(frames <-chan galaxy.Frame, reg *galaxy.CommandRegistry, fanout *emitter.Fanout) {
+	for f := range frames {
+		status := "OK"
+		if !f.Valid {
+			status = "INVALID CRC"
+		}
+
+		fmt.Printf("%s: %02X>%02X %s [%s]\n",
+			f.Timestamp.Format("15:04:05.000"), f.Address, f.CRC,
+			reg.Describe(f.Command, f.Payload), status)
+
+		if fanout != nil {
+			fanout.Send(f)
+		}
+	}
 
-	if len(bs) == 0 || bs[0] == 0x11 {
-		return interMessageGap
-	} else {
-		return slaveReplyGap
+	if fanout != nil {
+		fanout.Close()
 	}
 }
@@ -0,0 +1,171 @@
+package galaxy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Command identifies the byte immediately following a frame's address
+// byte in the Galaxy bus protocol.
+type Command byte
+
+const (
+	InitCommand   Command = 0x00
+	InitCommandOK Command = 0xFF
+	BadChecksum   Command = 0xF2
+	PollCommand   Command = 0x19
+	OK            Command = 0xFE
+	OKWithReply   Command = 0xF4
+	CommandC0     Command = 0xC0 // what is this sent often to keypad?
+	ScreenUpdate  Command = 0x07
+	Backlight     Command = 0x0D
+)
+
+// Describer renders the payload of a single Command into a human
+// readable description, and reports how many payload bytes the command
+// normally carries (-1 for variable length).
+type Describer interface {
+	Describe([]byte) string
+	ExpectLen() int
+}
+
+type simpleDescriber struct {
+	msg       string
+	expectLen int
+}
+
+func newSimple(expectLen int, msg string) Describer {
+	return &simpleDescriber{msg: msg, expectLen: expectLen}
+}
+
+func (d *simpleDescriber) Describe([]byte) string {
+	return d.msg
+}
+
+func (d *simpleDescriber) ExpectLen() int {
+	return d.expectLen
+}
+
+// hexBytes renders bs as space-separated hex pairs, e.g. "01 02". Unlike
+// fmt.Sprintf("% 02X", bs), it renders a zero-length bs as "" rather
+// than zero-padding the missing byte to "00", which would wrongly imply
+// a 0x00 byte was present.
+func hexBytes(bs []byte) string {
+	if len(bs) == 0 {
+		return ""
+	}
+
+	parts := make([]string, len(bs))
+	for i, b := range bs {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+type okWithReplyCommand struct{}
+
+func (*okWithReplyCommand) Describe(bs []byte) string {
+	if len(bs) != 1 {
+		return fmt.Sprintf("OK malformed reply: %s", hexBytes(bs))
+	}
+
+	data := bs[0]
+	if data == 0x7F {
+		return "OK TAMPER"
+	}
+
+	tamper := ""
+	if data&0x40 == 0x40 {
+		tamper = "TAMPER "
+	}
+
+	const keys = "01234567890BAEX*#"
+	return fmt.Sprintf("OK %sKEY %s", tamper, string(keys[data&0xF]))
+}
+
+func (*okWithReplyCommand) ExpectLen() int {
+	return 1
+}
+
+type backlightCommand struct{}
+
+func (*backlightCommand) Describe(bs []byte) string {
+	if len(bs) != 1 {
+		return fmt.Sprintf("BACKLIGHT malformed: %s", hexBytes(bs))
+	}
+
+	switch data := bs[0]; data {
+	case 0x00:
+		return "BACKLIGHT OFF"
+	case 0x01:
+		return "BACKLIGHT ON"
+	default:
+		return fmt.Sprintf("BACKLIGHT UNKNOWN %02X", data)
+	}
+}
+
+func (*backlightCommand) ExpectLen() int {
+	return 1
+}
+
+type screenUpdateCommand struct{}
+
+func (*screenUpdateCommand) Describe(bs []byte) string {
+	return fmt.Sprintf("SCREEN UPDATE: % 02X", bs)
+}
+
+func (*screenUpdateCommand) ExpectLen() int {
+	return -1
+}
+
+// CommandRegistry maps Commands to the Describer used to render their
+// payload, so callers can extend frame decoding with additional
+// commands without modifying this package.
+type CommandRegistry struct {
+	describers map[Command]Describer
+}
+
+// NewCommandRegistry returns a CommandRegistry pre-populated with the
+// known Galaxy bus commands.
+func NewCommandRegistry() *CommandRegistry {
+	r := &CommandRegistry{describers: make(map[Command]Describer)}
+
+	r.Register(InitCommand, newSimple(1, "INIT"))
+	r.Register(InitCommandOK, newSimple(0, "INIT OK"))
+	r.Register(BadChecksum, newSimple(0, "BAD CHK"))
+	r.Register(PollCommand, newSimple(1, "POLL"))
+	r.Register(OK, newSimple(0, "OK"))
+	r.Register(OKWithReply, &okWithReplyCommand{})
+	r.Register(CommandC0, newSimple(0, "CURRENTLY UNKNOWN COMMAND C0"))
+	r.Register(ScreenUpdate, &screenUpdateCommand{})
+	r.Register(Backlight, &backlightCommand{})
+
+	return r
+}
+
+// Register associates a Describer with cmd, replacing any existing
+// registration.
+func (r *CommandRegistry) Register(cmd Command, d Describer) {
+	r.describers[cmd] = d
+}
+
+// Describe renders bs using the Describer registered for cmd, or a
+// generic hex dump if no Describer is registered for it.
+func (r *CommandRegistry) Describe(cmd Command, bs []byte) string {
+	if d, ok := r.describers[cmd]; ok {
+		return d.Describe(bs)
+	}
+
+	return fmt.Sprintf("UNKNOWN COMMAND %02X: % 02X", byte(cmd), bs)
+}
+
+// ExpectLen reports the expected payload length for cmd, or -1 if cmd is
+// not registered or is variable length.
+func (r *CommandRegistry) ExpectLen(cmd Command) int {
+	if d, ok := r.describers[cmd]; ok {
+		return d.ExpectLen()
+	}
+
+	return -1
+}
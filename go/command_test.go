@@ -0,0 +1,66 @@
+package galaxy
+
+import "testing"
+
+func TestCommandRegistryDescribe(t *testing.T) {
+	reg := NewCommandRegistry()
+
+	for _, c := range []struct {
+		name string
+		cmd  Command
+		in   []byte
+		want string
+	}{
+		{name: "init", cmd: InitCommand, in: []byte{0x00}, want: "INIT"},
+		{name: "poll", cmd: PollCommand, in: []byte{0x01}, want: "POLL"},
+		{name: "ok", cmd: OK, in: nil, want: "OK"},
+		{name: "ok with reply, key", cmd: OKWithReply, in: []byte{0x03}, want: "OK KEY 3"},
+		{name: "ok with reply, tamper flag", cmd: OKWithReply, in: []byte{0x43}, want: "OK TAMPER KEY 3"},
+		{name: "ok with reply, tamper sentinel", cmd: OKWithReply, in: []byte{0x7F}, want: "OK TAMPER"},
+		{name: "ok with reply, malformed", cmd: OKWithReply, in: []byte{0x01, 0x02}, want: "OK malformed reply: 01 02"},
+		{name: "ok with reply, malformed, no payload", cmd: OKWithReply, in: []byte{}, want: "OK malformed reply: "},
+		{name: "backlight off", cmd: Backlight, in: []byte{0x00}, want: "BACKLIGHT OFF"},
+		{name: "backlight on", cmd: Backlight, in: []byte{0x01}, want: "BACKLIGHT ON"},
+		{name: "backlight unknown", cmd: Backlight, in: []byte{0x02}, want: "BACKLIGHT UNKNOWN 02"},
+		{name: "backlight malformed", cmd: Backlight, in: []byte{0x01, 0x02}, want: "BACKLIGHT malformed: 01 02"},
+		{name: "backlight malformed, no payload", cmd: Backlight, in: []byte{}, want: "BACKLIGHT malformed: "},
+		{name: "screen update", cmd: ScreenUpdate, in: []byte{0x01, 0x02}, want: "SCREEN UPDATE: 01 02"},
+		{name: "unregistered", cmd: Command(0x42), in: []byte{0x01}, want: "UNKNOWN COMMAND 42: 01"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			if got := reg.Describe(c.cmd, c.in); got != c.want {
+				t.Errorf("Describe(%#02x, % 02X) = %q, want %q", byte(c.cmd), c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCommandRegistryExpectLen(t *testing.T) {
+	reg := NewCommandRegistry()
+
+	for _, c := range []struct {
+		cmd  Command
+		want int
+	}{
+		{PollCommand, 1},
+		{OK, 0},
+		{ScreenUpdate, -1},
+		{Command(0x42), -1},
+	} {
+		if got := reg.ExpectLen(c.cmd); got != c.want {
+			t.Errorf("ExpectLen(%#02x) = %d, want %d", byte(c.cmd), got, c.want)
+		}
+	}
+}
+
+func TestCommandRegistryRegisterOverrides(t *testing.T) {
+	reg := NewCommandRegistry()
+	reg.Register(PollCommand, newSimple(2, "CUSTOM POLL"))
+
+	if got := reg.Describe(PollCommand, nil); got != "CUSTOM POLL" {
+		t.Errorf("Describe(PollCommand) = %q, want %q", got, "CUSTOM POLL")
+	}
+	if got := reg.ExpectLen(PollCommand); got != 2 {
+		t.Errorf("ExpectLen(PollCommand) = %d, want 2", got)
+	}
+}
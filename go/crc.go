@@ -0,0 +1,41 @@
+package galaxy
+
+import "hash"
+
+var _ hash.Hash = (*CRC)(nil)
+
+// CRC implements hash.Hash for the Galaxy bus's single-byte checksum: the
+// sum of every byte in the frame, offset by 0xAA and folded into a single
+// byte by repeated end-around carry.
+type CRC struct {
+	sum uint32
+}
+
+func (c *CRC) Write(p []byte) (int, error) {
+	for _, b := range p {
+		c.sum += uint32(b)
+	}
+
+	return len(p), nil
+}
+
+func (c *CRC) Sum(b []byte) []byte {
+	return append(b, c.Sum8())
+}
+
+// Sum8 returns the single checksum byte appended to every Galaxy bus
+// frame.
+func (c *CRC) Sum8() byte {
+	v := c.sum + 0xaa
+	for v > 0xFF {
+		v = (v >> 8) + (v & 0xFF)
+	}
+
+	return byte(v)
+}
+
+func (c *CRC) Reset() { c.sum = 0 }
+
+func (c *CRC) Size() int { return 1 }
+
+func (c *CRC) BlockSize() int { return 1 }
@@ -1,4 +1,4 @@
-package main
+package galaxy
 
 import "testing"
 
@@ -12,9 +12,9 @@ func TestCRC(t *testing.T) {
 			crc: 0xba,
 		},
 	} {
-		var crc GalaxyCRC
+		var crc CRC
 		crc.Write(c.in)
-		if sum := crc.Sum(); sum != c.crc {
+		if sum := crc.Sum8(); sum != c.crc {
 			t.Errorf("Expected CRC %02X got %02X", c.crc, sum)
 		}
 	}
@@ -0,0 +1,113 @@
+package galaxy
+
+import (
+	"bytes"
+	"context"
+	"time"
+)
+
+// Frame is a single decoded Galaxy bus frame: an address byte, a
+// Command byte, its payload, and the trailing CRC byte.
+type Frame struct {
+	Address   byte
+	Command   Command
+	Payload   []byte
+	CRC       byte
+	Valid     bool
+	Timestamp time.Time
+}
+
+// Decoder groups a stream of Messages into Frames using the
+// inter-message-gap heuristic (the bus falls silent between frames),
+// then validates each frame's trailing CRC byte.
+type Decoder struct {
+	// Registry describes the command byte of each decoded Frame. It is
+	// exported so callers can register additional commands.
+	Registry *CommandRegistry
+
+	// Gap is the minimum silence between reads that marks the boundary
+	// between two frames.
+	Gap time.Duration
+}
+
+// NewDecoder returns a Decoder using registry to describe commands. If
+// registry is nil, NewCommandRegistry is used.
+func NewDecoder(registry *CommandRegistry) *Decoder {
+	if registry == nil {
+		registry = NewCommandRegistry()
+	}
+
+	return &Decoder{Registry: registry, Gap: 5 * time.Millisecond}
+}
+
+// Decode consumes msgs, emitting a Frame each time the inter-message gap
+// elapses, and closes the returned channel once msgs is closed or ctx is
+// cancelled.
+func (d *Decoder) Decode(ctx context.Context, msgs <-chan Message) <-chan Frame {
+	out := make(chan Frame)
+
+	go func() {
+		defer close(out)
+
+		var frameStart, lastRead time.Time
+		var buf bytes.Buffer
+
+		emit := func() {
+			if f, ok := d.frame(frameStart, buf.Bytes()); ok {
+				select {
+				case out <- f:
+				case <-ctx.Done():
+				}
+			}
+			buf.Reset()
+		}
+
+		for {
+			select {
+			case msg, ok := <-msgs:
+				if !ok {
+					emit()
+					return
+				}
+
+				if !frameStart.IsZero() && msg.Timestamp.Sub(lastRead) > d.Gap {
+					emit()
+					frameStart = msg.Timestamp
+				}
+
+				lastRead = msg.Timestamp
+				if frameStart.IsZero() {
+					frameStart = lastRead
+				}
+
+				buf.Write(msg.Data)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
+
+// frame interprets bs as a single bus frame: a leading address byte, a
+// command byte, a payload, and a trailing CRC byte.
+func (d *Decoder) frame(ts time.Time, bs []byte) (Frame, bool) {
+	if len(bs) < 3 {
+		return Frame{}, false
+	}
+
+	body, crc := bs[:len(bs)-1], bs[len(bs)-1]
+
+	var c CRC
+	c.Write(body)
+
+	return Frame{
+		Address:   bs[0],
+		Command:   Command(bs[1]),
+		Payload:   bs[2 : len(bs)-1],
+		CRC:       crc,
+		Valid:     c.Sum8() == crc,
+		Timestamp: ts,
+	}, true
+}
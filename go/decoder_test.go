@@ -0,0 +1,83 @@
+package galaxy
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDecoderFrame(t *testing.T) {
+	d := NewDecoder(nil)
+	ts := time.UnixMicro(1700000000000000)
+
+	for _, c := range []struct {
+		name string
+		in   []byte
+		want Frame
+		ok   bool
+	}{
+		{
+			name: "too short",
+			in:   []byte{0x10, 0x19},
+			ok:   false,
+		},
+		{
+			name: "valid poll",
+			in:   []byte{0x10, 0x19, 0x01, 0xD4},
+			want: Frame{Address: 0x10, Command: PollCommand, Payload: []byte{0x01}, CRC: 0xD4, Valid: true, Timestamp: ts},
+			ok:   true,
+		},
+		{
+			name: "bad crc",
+			in:   []byte{0x10, 0x19, 0x01, 0x00},
+			want: Frame{Address: 0x10, Command: PollCommand, Payload: []byte{0x01}, CRC: 0x00, Valid: false, Timestamp: ts},
+			ok:   true,
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := d.frame(ts, c.in)
+			if ok != c.ok {
+				t.Fatalf("frame() ok = %v, want %v", ok, c.ok)
+			}
+			if !ok {
+				return
+			}
+
+			if got.Address != c.want.Address || got.Command != c.want.Command || got.CRC != c.want.CRC || got.Valid != c.want.Valid {
+				t.Errorf("frame() = %+v, want %+v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDecoderDecodeSplitsOnGap(t *testing.T) {
+	d := NewDecoder(nil)
+	d.Gap = 10 * time.Millisecond
+
+	msgs := make(chan Message)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	frames := d.Decode(ctx, msgs)
+
+	base := time.UnixMicro(1700000000000000)
+	msgs <- Message{Data: []byte{0x10, 0x19, 0x01}, Timestamp: base}
+	msgs <- Message{Data: []byte{0xD4}, Timestamp: base.Add(time.Millisecond)}
+	msgs <- Message{Data: []byte{0x11, 0xFE, 0xBA}, Timestamp: base.Add(time.Second)}
+	close(msgs)
+
+	var got []Frame
+	for f := range frames {
+		got = append(got, f)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d frames, want 2: %+v", len(got), got)
+	}
+	if got[0].Command != PollCommand || !got[0].Valid {
+		t.Errorf("frame 0 = %+v, want a valid poll frame", got[0])
+	}
+	if got[1].Address != 0x11 || !got[1].Valid {
+		t.Errorf("frame 1 = %+v, want a valid frame from address 0x11", got[1])
+	}
+}
@@ -0,0 +1,94 @@
+// Package emitter fans decoded Frames out to configurable sinks - stdout
+// or file JSON Lines, MQTT, and OTLP logs - so a sniff session can feed a
+// home-automation bus or an observability pipeline without
+// post-processing capture files.
+package emitter
+
+import (
+	"log"
+
+	"go.tigermatt.uk/galaxy"
+)
+
+// Sink receives decoded Frames. A Sink's Emit may block or be slow;
+// Fanout isolates each Sink on its own bounded queue so a stalled Sink
+// cannot hold up the others or the frame decoder.
+type Sink interface {
+	Emit(galaxy.Frame) error
+	Close() error
+}
+
+// queueDepth bounds how many Frames may be buffered for a Sink that is
+// falling behind before its frames start being dropped.
+const queueDepth = 256
+
+// Fanout delivers Frames to a fixed set of Sinks concurrently and
+// without blocking the caller.
+type Fanout struct {
+	sinks []*queuedSink
+}
+
+// New returns a Fanout delivering to each of sinks.
+func New(sinks ...Sink) *Fanout {
+	f := &Fanout{}
+	for _, s := range sinks {
+		f.sinks = append(f.sinks, startQueuedSink(s))
+	}
+
+	return f
+}
+
+// Send enqueues frame for every Sink. It never blocks: a Sink whose
+// queue is full has this frame dropped for it.
+func (f *Fanout) Send(frame galaxy.Frame) {
+	for _, s := range f.sinks {
+		s.send(frame)
+	}
+}
+
+// Close drains and closes every Sink, waiting for each to finish any
+// Frames already queued.
+func (f *Fanout) Close() {
+	for _, s := range f.sinks {
+		s.stop()
+	}
+}
+
+type queuedSink struct {
+	sink Sink
+	ch   chan galaxy.Frame
+	done chan struct{}
+}
+
+func startQueuedSink(sink Sink) *queuedSink {
+	q := &queuedSink{sink: sink, ch: make(chan galaxy.Frame, queueDepth), done: make(chan struct{})}
+
+	go func() {
+		defer close(q.done)
+
+		for frame := range q.ch {
+			if err := sink.Emit(frame); err != nil {
+				log.Printf("emitter: %T: %s", sink, err)
+			}
+		}
+	}()
+
+	return q
+}
+
+func (q *queuedSink) send(frame galaxy.Frame) {
+	select {
+	case q.ch <- frame:
+	default:
+		log.Printf("emitter: %T: queue full, dropping frame", q.sink)
+	}
+}
+
+func (q *queuedSink) stop() {
+	close(q.ch)
+	<-q.done
+
+	if err := q.sink.Close(); err != nil {
+		log.Printf("emitter: %T: closing: %s", q.sink, err)
+	}
+}
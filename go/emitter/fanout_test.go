@@ -0,0 +1,122 @@
+package emitter
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.tigermatt.uk/galaxy"
+)
+
+// fakeSink counts Emit/Close calls and, while blocking is true, makes
+// every Emit hang until unblock is closed.
+type fakeSink struct {
+	mu       sync.Mutex
+	emitted  []galaxy.Frame
+	closed   bool
+	blocking bool
+	unblock  chan struct{}
+}
+
+func (s *fakeSink) Emit(f galaxy.Frame) error {
+	if s.blocking {
+		<-s.unblock
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitted = append(s.emitted, f)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.emitted)
+}
+
+func (s *fakeSink) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// TestFanoutSendNeverBlocks guards the package doc's "Send ... never
+// blocks" claim: with a Sink wedged on Emit and its queue already full,
+// Send must still return promptly rather than stalling the caller (the
+// serial reader, in practice) behind it.
+func TestFanoutSendNeverBlocks(t *testing.T) {
+	sink := &fakeSink{blocking: true, unblock: make(chan struct{})}
+	f := New(sink)
+	defer f.Close()
+	defer close(sink.unblock) // unblock the stalled sink before Close waits to drain it
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < queueDepth+10; i++ {
+			f.Send(galaxy.Frame{Address: byte(i)})
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked with a full queue and a stalled sink")
+	}
+}
+
+// TestFanoutSendDropsOnFullQueue checks that frames beyond the bounded
+// queue are dropped rather than buffered without limit, while the sink
+// is stalled. The sink's single worker goroutine dequeues at most one
+// frame before blocking for good in Emit, so however the test's sends
+// race against that first dequeue, at most queueDepth+1 of them can
+// ever be accepted - strictly fewer than the queueDepth+10 attempted.
+func TestFanoutSendDropsOnFullQueue(t *testing.T) {
+	const sent = queueDepth + 10
+
+	sink := &fakeSink{blocking: true, unblock: make(chan struct{})}
+
+	f := New(sink)
+	for i := 0; i < sent; i++ {
+		f.Send(galaxy.Frame{Address: byte(i)})
+	}
+
+	close(sink.unblock)
+	f.Close()
+
+	if got := sink.count(); got == 0 || got > queueDepth+1 {
+		t.Fatalf("sink received %d frames, want 1..%d", got, queueDepth+1)
+	}
+	if got := sink.count(); got >= sent {
+		t.Errorf("sink received %d frames, want fewer than the %d sent (none dropped)", got, sent)
+	}
+}
+
+// TestFanoutCloseDrainsBeforeClosing checks that Close waits for every
+// already-queued Frame to reach the Sink before calling Sink.Close.
+func TestFanoutCloseDrainsBeforeClosing(t *testing.T) {
+	sink := &fakeSink{}
+	f := New(sink)
+
+	const n = 50
+	for i := 0; i < n; i++ {
+		f.Send(galaxy.Frame{Address: byte(i)})
+	}
+
+	f.Close()
+
+	if got := sink.count(); got != n {
+		t.Errorf("sink received %d frames before Close returned, want %d", got, n)
+	}
+	if !sink.isClosed() {
+		t.Error("sink was not closed")
+	}
+}
@@ -0,0 +1,68 @@
+package emitter
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"go.tigermatt.uk/galaxy"
+)
+
+// JSONLinesSink writes one JSON object per Frame to an io.Writer.
+type JSONLinesSink struct {
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewJSONLinesSink writes JSON Lines to w. Close is a no-op: w may be
+// shared (e.g. os.Stdout), so NewJSONLinesSink never closes it. Use
+// NewJSONLinesFileSink for a sink that owns, and closes, its own file.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w}
+}
+
+// NewJSONLinesFileSink writes JSON Lines to the file at path, creating
+// or truncating it. Unlike NewJSONLinesSink, Close closes the file.
+func NewJSONLinesFileSink(path string) (*JSONLinesSink, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONLinesSink{w: f, closer: f}, nil
+}
+
+type jsonFrame struct {
+	Timestamp time.Time `json:"timestamp"`
+	Address   byte      `json:"address"`
+	Command   byte      `json:"command"`
+	Payload   string    `json:"payload"`
+	CRCValid  bool      `json:"crc_valid"`
+}
+
+func (s *JSONLinesSink) Emit(f galaxy.Frame) error {
+	bs, err := json.Marshal(jsonFrame{
+		Timestamp: f.Timestamp,
+		Address:   f.Address,
+		Command:   byte(f.Command),
+		Payload:   hex.EncodeToString(f.Payload),
+		CRCValid:  f.Valid,
+	})
+	if err != nil {
+		return err
+	}
+
+	bs = append(bs, '\n')
+	_, err = s.w.Write(bs)
+	return err
+}
+
+func (s *JSONLinesSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	return s.closer.Close()
+}
@@ -0,0 +1,50 @@
+package emitter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+
+	"go.tigermatt.uk/galaxy"
+)
+
+// publishTimeout bounds how long a single MQTT publish may block the
+// sink's queue goroutine.
+const publishTimeout = 5 * time.Second
+
+// MQTTSink publishes each Frame under a topic derived from its address
+// and command, e.g. "<topicRoot>/10/19", so subscribers can filter by
+// either without inspecting the payload.
+type MQTTSink struct {
+	client    mqtt.Client
+	topicRoot string
+}
+
+// NewMQTTSink connects to the broker at brokerURL (e.g.
+// "tcp://localhost:1883") and publishes frames under topicRoot.
+func NewMQTTSink(brokerURL, topicRoot string) (*MQTTSink, error) {
+	opts := mqtt.NewClientOptions().AddBroker(brokerURL).SetClientID("serialsniff")
+
+	client := mqtt.NewClient(opts)
+	if tok := client.Connect(); tok.Wait() && tok.Error() != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", brokerURL, tok.Error())
+	}
+
+	return &MQTTSink{client: client, topicRoot: topicRoot}, nil
+}
+
+func (s *MQTTSink) Emit(f galaxy.Frame) error {
+	topic := fmt.Sprintf("%s/%02x/%02x", s.topicRoot, f.Address, byte(f.Command))
+
+	tok := s.client.Publish(topic, 0, false, hex.EncodeToString(f.Payload))
+	tok.WaitTimeout(publishTimeout)
+
+	return tok.Error()
+}
+
+func (s *MQTTSink) Close() error {
+	s.client.Disconnect(250)
+	return nil
+}
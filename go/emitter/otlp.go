@@ -0,0 +1,52 @@
+package emitter
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"go.tigermatt.uk/galaxy"
+)
+
+// OTLPSink exports each Frame as an OTLP log record over gRPC, with
+// attributes galaxy.address, galaxy.command and galaxy.crc_valid and the
+// hex payload as the record body.
+type OTLPSink struct {
+	provider *sdklog.LoggerProvider
+	logger   log.Logger
+}
+
+// NewOTLPSink dials the OTLP/logs gRPC endpoint at target (host:port).
+func NewOTLPSink(ctx context.Context, target string) (*OTLPSink, error) {
+	exporter, err := otlploggrpc.New(ctx, otlploggrpc.WithEndpoint(target), otlploggrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP log exporter: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+
+	return &OTLPSink{provider: provider, logger: provider.Logger("go.tigermatt.uk/galaxy/serialsniff")}, nil
+}
+
+func (s *OTLPSink) Emit(f galaxy.Frame) error {
+	var rec log.Record
+	rec.SetTimestamp(f.Timestamp)
+	rec.SetBody(log.StringValue(hex.EncodeToString(f.Payload)))
+	rec.AddAttributes(
+		log.KeyValue{Key: "galaxy.address", Value: log.StringValue(fmt.Sprintf("%02x", f.Address))},
+		log.KeyValue{Key: "galaxy.command", Value: log.StringValue(fmt.Sprintf("%02x", byte(f.Command)))},
+		log.KeyValue{Key: "galaxy.crc_valid", Value: log.BoolValue(f.Valid)},
+	)
+
+	s.logger.Emit(context.Background(), rec)
+
+	return nil
+}
+
+func (s *OTLPSink) Close() error {
+	return s.provider.Shutdown(context.Background())
+}
@@ -0,0 +1,20 @@
+package galaxy
+
+import "io"
+
+// MessageEncoder writes successive Messages to a capture stream.
+type MessageEncoder interface {
+	Encode(Message) error
+}
+
+// MessageDecoder reads successive Messages from a capture stream. Decode
+// returns io.EOF once the stream is exhausted.
+type MessageDecoder interface {
+	Decode(*Message) error
+}
+
+// Format is a pluggable capture file format for Recorder and ReadIn.
+type Format interface {
+	Encoder(io.Writer) MessageEncoder
+	Decoder(io.Reader) MessageDecoder
+}
@@ -0,0 +1,35 @@
+package galaxy
+
+import (
+	"encoding/gob"
+	"io"
+)
+
+// GobFormat is the original capture format: a raw encoding/gob stream of
+// Messages. It is Go-only and opaque to standard tooling, but requires
+// no framing of its own.
+type GobFormat struct{}
+
+func (GobFormat) Encoder(w io.Writer) MessageEncoder {
+	return &gobEncoder{enc: gob.NewEncoder(w)}
+}
+
+func (GobFormat) Decoder(r io.Reader) MessageDecoder {
+	return &gobDecoder{dec: gob.NewDecoder(r)}
+}
+
+type gobEncoder struct {
+	enc *gob.Encoder
+}
+
+func (e *gobEncoder) Encode(msg Message) error {
+	return e.enc.Encode(msg)
+}
+
+type gobDecoder struct {
+	dec *gob.Decoder
+}
+
+func (d *gobDecoder) Decode(msg *Message) error {
+	return d.dec.Decode(msg)
+}
@@ -0,0 +1,93 @@
+package galaxy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// JSONLinesFormat records one JSON object per line, so captures can be
+// inspected with grep or jq without any special tooling.
+type JSONLinesFormat struct{}
+
+type jsonMessage struct {
+	Timestamp time.Time `json:"timestamp"`
+	Data      string    `json:"data"`
+}
+
+func (JSONLinesFormat) Encoder(w io.Writer) MessageEncoder {
+	return &jsonlEncoder{w: w}
+}
+
+func (JSONLinesFormat) Decoder(r io.Reader) MessageDecoder {
+	return &jsonlDecoder{br: bufio.NewReader(r)}
+}
+
+type jsonlEncoder struct {
+	w io.Writer
+}
+
+func (e *jsonlEncoder) Encode(msg Message) error {
+	bs, err := json.Marshal(jsonMessage{
+		Timestamp: msg.Timestamp,
+		Data:      hex.EncodeToString(msg.Data),
+	})
+	if err != nil {
+		return err
+	}
+
+	bs = append(bs, '\n')
+	_, err = e.w.Write(bs)
+	return err
+}
+
+// jsonlDecoder reads lines with bufio.Reader rather than bufio.Scanner,
+// because a Scanner caches the first error (including io.EOF) it sees
+// and never calls Read again: that would permanently stop a follow-mode
+// reader the moment it first caught up with a concurrent writer. A
+// torn, not-yet-newline-terminated trailing line is buffered in pending
+// and completed on a later Decode call once the rest of it arrives.
+type jsonlDecoder struct {
+	br      *bufio.Reader
+	pending []byte
+}
+
+func (d *jsonlDecoder) Decode(msg *Message) error {
+	for {
+		line, err := d.br.ReadBytes('\n')
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return err
+			}
+
+			d.pending = append(d.pending, line...)
+			return io.EOF
+		}
+
+		line = append(d.pending, line...)
+		d.pending = nil
+
+		line = bytes.TrimRight(line, "\n")
+		if len(line) == 0 {
+			continue
+		}
+
+		var jm jsonMessage
+		if err := json.Unmarshal(line, &jm); err != nil {
+			return err
+		}
+
+		data, err := hex.DecodeString(jm.Data)
+		if err != nil {
+			return err
+		}
+
+		msg.Timestamp = jm.Timestamp
+		msg.Data = data
+		return nil
+	}
+}
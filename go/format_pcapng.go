@@ -0,0 +1,225 @@
+package galaxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PCAPNGFormat records a pcap-ng capture using a private link-layer type
+// (LINKTYPE_USER0), so captures can be opened directly in Wireshark with
+// a custom Lua dissector. Each packet is prefixed with a small header
+// carrying the frame direction, inferred from the bus address byte.
+type PCAPNGFormat struct{}
+
+const (
+	pcapngBlockSHB = 0x0A0D0D0A
+	pcapngBlockIDB = 0x00000001
+	pcapngBlockEPB = 0x00000006
+
+	pcapngByteOrderMagic = 0x1A2B3C4D
+
+	// LINKTYPE_USER0, reserved by tcpdump for experimental link types.
+	linkTypeUser0 = 147
+)
+
+// Frame direction, carried in the first byte of the per-packet header.
+const (
+	directionUnknown byte = iota
+	directionMasterToSlave
+	directionSlaveToMaster
+)
+
+func (PCAPNGFormat) Encoder(w io.Writer) MessageEncoder {
+	return &pcapngEncoder{w: w}
+}
+
+func (PCAPNGFormat) Decoder(r io.Reader) MessageDecoder {
+	return &pcapngDecoder{r: r}
+}
+
+type pcapngEncoder struct {
+	w           io.Writer
+	wroteHeader bool
+}
+
+func (e *pcapngEncoder) Encode(msg Message) error {
+	if !e.wroteHeader {
+		if err := writeSectionHeader(e.w); err != nil {
+			return err
+		}
+		if err := writeInterfaceDescription(e.w); err != nil {
+			return err
+		}
+		e.wroteHeader = true
+	}
+
+	return writePacket(e.w, msg)
+}
+
+func writeSectionHeader(w io.Writer) error {
+	body := make([]byte, 16)
+	binary.LittleEndian.PutUint32(body[0:4], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(body[4:6], 1) // major version
+	binary.LittleEndian.PutUint16(body[6:8], 0) // minor version
+	binary.LittleEndian.PutUint64(body[8:16], 0xFFFFFFFFFFFFFFFF)
+
+	return writeBlock(w, pcapngBlockSHB, body)
+}
+
+func writeInterfaceDescription(w io.Writer) error {
+	body := make([]byte, 8)
+	binary.LittleEndian.PutUint16(body[0:2], linkTypeUser0)
+	binary.LittleEndian.PutUint16(body[2:4], 0)      // reserved
+	binary.LittleEndian.PutUint32(body[4:8], 0xFFFF) // snaplen (no limit)
+
+	return writeBlock(w, pcapngBlockIDB, body)
+}
+
+func writePacket(w io.Writer, msg Message) error {
+	header := []byte{packetDirection(msg.Data), 0}
+	data := append(header, msg.Data...)
+
+	micros := uint64(msg.Timestamp.UnixMicro())
+
+	body := make([]byte, 20+pad4(len(data)))
+	binary.LittleEndian.PutUint32(body[0:4], 0) // interface id
+	binary.LittleEndian.PutUint32(body[4:8], uint32(micros>>32))
+	binary.LittleEndian.PutUint32(body[8:12], uint32(micros))
+	binary.LittleEndian.PutUint32(body[12:16], uint32(len(data)))
+	binary.LittleEndian.PutUint32(body[16:20], uint32(len(data)))
+	copy(body[20:], data)
+
+	return writeBlock(w, pcapngBlockEPB, body)
+}
+
+func writeBlock(w io.Writer, blockType uint32, body []byte) error {
+	total := 12 + len(body)
+
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], blockType)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(total))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+
+	trailer := make([]byte, 4)
+	binary.LittleEndian.PutUint32(trailer, uint32(total))
+	_, err := w.Write(trailer)
+	return err
+}
+
+func packetDirection(bs []byte) byte {
+	if len(bs) == 0 {
+		return directionUnknown
+	}
+
+	switch bs[0] {
+	case 0x10:
+		return directionMasterToSlave
+	case 0x11:
+		return directionSlaveToMaster
+	default:
+		return directionUnknown
+	}
+}
+
+func pad4(n int) int {
+	if rem := n % 4; rem != 0 {
+		return n + (4 - rem)
+	}
+	return n
+}
+
+type pcapngDecoder struct {
+	r              io.Reader
+	skippedHeaders bool
+}
+
+func (d *pcapngDecoder) Decode(msg *Message) error {
+	if !d.skippedHeaders {
+		if err := d.skipHeaders(); err != nil {
+			return err
+		}
+		d.skippedHeaders = true
+	}
+
+	for {
+		blockType, body, err := readBlock(d.r)
+		if err != nil {
+			return err
+		}
+
+		if blockType != pcapngBlockEPB {
+			continue
+		}
+
+		return decodeEnhancedPacket(body, msg)
+	}
+}
+
+func (d *pcapngDecoder) skipHeaders() error {
+	for _, want := range []uint32{pcapngBlockSHB, pcapngBlockIDB} {
+		blockType, _, err := readBlock(d.r)
+		if err != nil {
+			return err
+		}
+		if blockType != want {
+			return fmt.Errorf("pcapng: unexpected block type %#x, wanted %#x", blockType, want)
+		}
+	}
+
+	return nil
+}
+
+func readBlock(r io.Reader) (blockType uint32, body []byte, err error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	blockType = binary.LittleEndian.Uint32(header[0:4])
+	total := binary.LittleEndian.Uint32(header[4:8])
+	if total < 12 {
+		return 0, nil, errors.New("pcapng: block too short")
+	}
+
+	body = make([]byte, total-12)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+
+	trailer := make([]byte, 4)
+	if _, err := io.ReadFull(r, trailer); err != nil {
+		return 0, nil, err
+	}
+
+	return blockType, body, nil
+}
+
+func decodeEnhancedPacket(body []byte, msg *Message) error {
+	if len(body) < 20 {
+		return errors.New("pcapng: enhanced packet block too short")
+	}
+
+	tsHigh := binary.LittleEndian.Uint32(body[4:8])
+	tsLow := binary.LittleEndian.Uint32(body[8:12])
+	capLen := binary.LittleEndian.Uint32(body[12:16])
+
+	if int(capLen) > len(body)-20 || capLen < 2 {
+		return errors.New("pcapng: malformed packet length")
+	}
+
+	data := body[20 : 20+capLen]
+
+	msg.Timestamp = time.UnixMicro(int64(tsHigh)<<32 | int64(tsLow))
+	msg.Data = append([]byte(nil), data[2:]...)
+
+	return nil
+}
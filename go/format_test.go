@@ -0,0 +1,143 @@
+package galaxy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFormatRoundTrip(t *testing.T) {
+	msgs := []Message{
+		{Data: []byte{0x10, 0x19, 0x01, 0xD4}, Timestamp: time.UnixMicro(1700000000000000)},
+		{Data: []byte{0x11, 0xFE, 0xBA}, Timestamp: time.UnixMicro(1700000000010000)},
+	}
+
+	for _, format := range []Format{GobFormat{}, JSONLinesFormat{}, PCAPNGFormat{}} {
+		var buf bytes.Buffer
+
+		enc := format.Encoder(&buf)
+		for _, msg := range msgs {
+			if err := enc.Encode(msg); err != nil {
+				t.Fatalf("%T: encoding: %s", format, err)
+			}
+		}
+
+		dec := format.Decoder(&buf)
+		for i, want := range msgs {
+			var got Message
+			if err := dec.Decode(&got); err != nil {
+				t.Fatalf("%T: decoding message %d: %s", format, i, err)
+			}
+
+			if !bytes.Equal(got.Data, want.Data) {
+				t.Errorf("%T: message %d data = % 02X, want % 02X", format, i, got.Data, want.Data)
+			}
+			if !got.Timestamp.Equal(want.Timestamp) {
+				t.Errorf("%T: message %d timestamp = %s, want %s", format, i, got.Timestamp, want.Timestamp)
+			}
+		}
+	}
+}
+
+// tailReader simulates a file a concurrent writer is still appending
+// to: reads past the bytes written so far return io.EOF rather than
+// blocking, but later reads see whatever append added in the meantime.
+type tailReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *tailReader) Read(p []byte) (int, error) {
+	if r.pos >= len(r.buf) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.buf[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+func (r *tailReader) Write(p []byte) (int, error) {
+	r.buf = append(r.buf, p...)
+	return len(p), nil
+}
+
+func (r *tailReader) append(bs []byte) {
+	r.buf = append(r.buf, bs...)
+}
+
+// TestJSONLinesDecoderResumesAfterEOF guards against a bufio.Scanner
+// regression: a Scanner caches the first error it sees, including
+// io.EOF, and refuses to call Read again - which would permanently wedge
+// a follow-mode reader the moment it first caught up with a writer.
+func TestJSONLinesDecoderResumesAfterEOF(t *testing.T) {
+	msg1 := Message{Data: []byte{0x10, 0x19, 0x01, 0xD4}, Timestamp: time.UnixMicro(1700000000000000)}
+	msg2 := Message{Data: []byte{0x10, 0x07, 0x02, 0x03, 0x04, 0x05}, Timestamp: time.UnixMicro(1700000000010000)}
+
+	r := &tailReader{}
+	dec := JSONLinesFormat{}.Decoder(r)
+	enc := JSONLinesFormat{}.Encoder(r)
+
+	if err := enc.Encode(msg1); err != nil {
+		t.Fatalf("encoding msg1: %s", err)
+	}
+
+	var got Message
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("decoding msg1: %s", err)
+	}
+	if !bytes.Equal(got.Data, msg1.Data) {
+		t.Fatalf("msg1 = % 02X, want % 02X", got.Data, msg1.Data)
+	}
+
+	if err := dec.Decode(&got); !errors.Is(err, io.EOF) {
+		t.Fatalf("decoding at EOF = %v, want io.EOF", err)
+	}
+
+	if err := enc.Encode(msg2); err != nil {
+		t.Fatalf("encoding msg2: %s", err)
+	}
+
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("decoding msg2 after a prior EOF: %s", err)
+	}
+	if !bytes.Equal(got.Data, msg2.Data) {
+		t.Fatalf("msg2 = % 02X, want % 02X", got.Data, msg2.Data)
+	}
+}
+
+// TestJSONLinesDecoderResumesAfterTornLine covers a writer whose Write
+// of a line is torn between two syscalls, so Decode sees an incomplete,
+// not-yet-newline-terminated line before the rest of it arrives.
+func TestJSONLinesDecoderResumesAfterTornLine(t *testing.T) {
+	msg := Message{Data: []byte{0x10, 0x07, 0x02, 0x03, 0x04, 0x05}, Timestamp: time.UnixMicro(1700000000000000)}
+
+	var buf bytes.Buffer
+	enc := JSONLinesFormat{}.Encoder(&buf)
+	if err := enc.Encode(msg); err != nil {
+		t.Fatalf("encoding: %s", err)
+	}
+	line := buf.Bytes()
+	tornAt := len(line) / 2
+
+	r := &tailReader{}
+	r.append(line[:tornAt])
+
+	dec := JSONLinesFormat{}.Decoder(r)
+
+	var got Message
+	if err := dec.Decode(&got); !errors.Is(err, io.EOF) {
+		t.Fatalf("decoding torn line = %v, want io.EOF", err)
+	}
+
+	r.append(line[tornAt:])
+
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("decoding completed line: %s", err)
+	}
+	if !bytes.Equal(got.Data, msg.Data) {
+		t.Fatalf("message = % 02X, want % 02X", got.Data, msg.Data)
+	}
+}
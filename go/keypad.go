@@ -0,0 +1,238 @@
+package galaxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// Bootstrap commands sent once when a Keypad starts talking to the bus:
+// init, poll, backlight on, and beep. cmdInit and cmdPoll already carry
+// their checksum byte; cmdBacklightOn and cmdBeep have theirs computed
+// by withCRC.
+var (
+	cmdInit        = []byte{0x10, 0x00, 0x0E, 0xC8}
+	cmdPoll        = []byte{0x10, 0x19, 0x01, 0xD4}
+	cmdBacklightOn = withCRC(0x10, 0x0D, 0x01)
+	cmdBeep        = withCRC(0x10, 0x0C, 0x00, 0x00, 0x00)
+)
+
+func withCRC(bs ...byte) []byte {
+	var c CRC
+	c.Write(bs)
+	return c.Sum(append([]byte(nil), bs...))
+}
+
+const keypadKeys = "0123456789BAEX*#"
+
+// Keypad emulates a Galaxy bus keypad: it polls the bus, drives a
+// two-line display, and reports key presses and tamper state, so the
+// decoder and keypad-handling code can be exercised without the
+// physical panel connected.
+type Keypad struct {
+	mu sync.Mutex
+
+	lines    [2]string
+	tamper   bool
+	lastKey  byte
+	keyTime  time.Time
+	ack      byte
+	ackKey   bool
+	toggle07 byte
+}
+
+// NewKeypad returns a Keypad initially displaying line0 and line1.
+func NewKeypad(line0, line1 string) *Keypad {
+	return &Keypad{
+		lines:    [2]string{line0, line1},
+		ack:      0x02,
+		toggle07: 0x80,
+	}
+}
+
+// SetLine sets the text displayed on row (0 or 1), truncated or padded
+// to the keypad's 16-character display on the next screen update.
+func (k *Keypad) SetLine(row int, text string) {
+	if row < 0 || row > 1 {
+		return
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.lines[row] = text
+}
+
+// PressKey records r as the most recently pressed key, to be reported
+// and acknowledged on the next poll.
+func (k *Keypad) PressKey(r rune) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.lastKey = byte(r)
+	k.keyTime = time.Now()
+	k.ackKey = true
+}
+
+// SetTamper sets the tamper switch state reported on the next poll.
+func (k *Keypad) SetTamper(tamper bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.tamper = tamper
+}
+
+// Serve runs the Keypad's bootstrap, poll and screen-update loop against
+// port until ctx is cancelled.
+func (k *Keypad) Serve(ctx context.Context, port serial.Port) error {
+	for _, cmd := range [][]byte{cmdInit, cmdPoll, cmdBacklightOn, cmdBeep} {
+		if _, err := k.exchange(port, cmd); err != nil {
+			return fmt.Errorf("bootstrapping keypad: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		k.mu.Lock()
+		ackKey := k.ackKey
+		k.mu.Unlock()
+
+		if ackKey {
+			if err := k.sendAck(port); err != nil {
+				return fmt.Errorf("acking key: %w", err)
+			}
+
+			continue
+		}
+
+		reply, err := k.exchange(port, cmdPoll)
+		if err != nil {
+			return fmt.Errorf("polling: %w", err)
+		}
+
+		if len(reply) > 1 {
+			switch Command(reply[1]) {
+			case OKWithReply:
+				if len(reply) > 2 {
+					k.handleKeyTamper(reply[2])
+				}
+			case OK:
+				k.mu.Lock()
+				k.tamper = false
+				k.mu.Unlock()
+			}
+		}
+
+		if err := k.sendScreen(port); err != nil {
+			return fmt.Errorf("updating screen: %w", err)
+		}
+	}
+}
+
+func (k *Keypad) handleKeyTamper(b byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if b == 0x7F {
+		k.tamper = true
+		return
+	}
+
+	k.tamper = b&0x40 == 0x40
+	k.lastKey = keypadKeys[b&0xF]
+	k.keyTime = time.Now()
+	k.ackKey = true
+}
+
+func (k *Keypad) sendAck(port serial.Port) error {
+	k.mu.Lock()
+	ack := k.ack
+	k.mu.Unlock()
+
+	if _, err := k.exchange(port, withCRC(0x10, 0x0B, ack)); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.ack ^= 0x02
+	k.ackKey = false
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *Keypad) sendScreen(port serial.Port) error {
+	k.mu.Lock()
+	flags := byte(0x01) | k.toggle07
+	if k.ackKey {
+		flags |= 0x10 | k.ack
+		k.ack ^= 0x02
+		k.ackKey = false
+	}
+	line0 := padLine(k.lines[0], time.Since(k.keyTime) < 3*time.Second, k.lastKey)
+	line1 := padLine(k.lines[1], k.tamper, 'T')
+	k.toggle07 ^= 0x80
+	k.mu.Unlock()
+
+	blink := []byte{0x10, 0x07, flags, 0x01, 0x07}
+	blink = append(blink, line0...)
+	blink = append(blink, 0x02)
+	blink = append(blink, line1...)
+
+	_, err := k.exchange(port, withCRC(blink...))
+	return err
+}
+
+// padLine truncates or space-pads text to the keypad's 16-character
+// display width, overlaying its last character with overlayByte if
+// overlay is set.
+func padLine(text string, overlay bool, overlayByte byte) []byte {
+	bs := []byte(text)
+	if len(bs) > 16 {
+		bs = bs[:16]
+	}
+	for len(bs) < 16 {
+		bs = append(bs, 0x20)
+	}
+
+	if overlay {
+		bs[15] = overlayByte
+	}
+
+	return bs
+}
+
+// exchange writes bs to port and returns whatever bytes are read back
+// within a short reply window, mirroring the real bus's turnaround time.
+func (k *Keypad) exchange(port serial.Port, bs []byte) ([]byte, error) {
+	if _, err := port.Write(bs); err != nil {
+		return nil, err
+	}
+
+	time.Sleep(time.Second * time.Duration((10*len(bs)+1)/9600))
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	reply := make([]byte, 128)
+	var n int
+
+	for time.Now().Before(deadline) {
+		got, err := port.Read(reply[n:])
+		n += got
+		if err != nil {
+			if err == io.EOF {
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return reply[:n], nil
+}
@@ -0,0 +1,173 @@
+package galaxy
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// fakePort is a minimal serial.Port that records every Write and never
+// has a reply waiting, so exchange's read loop runs out its deadline
+// and returns quickly with nothing read.
+type fakePort struct {
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func (p *fakePort) Write(bs []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.writes = append(p.writes, append([]byte(nil), bs...))
+	return len(bs), nil
+}
+
+func (p *fakePort) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (p *fakePort) last() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.writes[len(p.writes)-1]
+}
+
+func (*fakePort) SetMode(*serial.Mode) error                           { return nil }
+func (*fakePort) Drain() error                                         { return nil }
+func (*fakePort) ResetInputBuffer() error                              { return nil }
+func (*fakePort) ResetOutputBuffer() error                             { return nil }
+func (*fakePort) SetDTR(bool) error                                    { return nil }
+func (*fakePort) SetRTS(bool) error                                    { return nil }
+func (*fakePort) GetModemStatusBits() (*serial.ModemStatusBits, error) { return nil, nil }
+func (*fakePort) SetReadTimeout(time.Duration) error                   { return nil }
+func (*fakePort) Close() error                                         { return nil }
+func (*fakePort) Break(time.Duration) error                            { return nil }
+
+func TestKeypadSetLinePressKeySetTamper(t *testing.T) {
+	k := NewKeypad("line0", "line1")
+
+	k.SetLine(0, "hello")
+	k.SetLine(1, "world")
+	if k.lines[0] != "hello" || k.lines[1] != "world" {
+		t.Fatalf("lines = %q, %q, want %q, %q", k.lines[0], k.lines[1], "hello", "world")
+	}
+
+	k.SetLine(-1, "ignored")
+	k.SetLine(2, "ignored")
+	if k.lines[0] != "hello" || k.lines[1] != "world" {
+		t.Fatalf("out-of-range SetLine mutated lines: %q, %q", k.lines[0], k.lines[1])
+	}
+
+	k.PressKey('5')
+	if k.lastKey != '5' || !k.ackKey {
+		t.Fatalf("after PressKey('5'): lastKey = %q, ackKey = %v, want '5', true", k.lastKey, k.ackKey)
+	}
+	if time.Since(k.keyTime) > time.Second {
+		t.Errorf("keyTime = %s, want close to now", k.keyTime)
+	}
+
+	k.SetTamper(true)
+	if !k.tamper {
+		t.Fatal("SetTamper(true) did not set tamper")
+	}
+	k.SetTamper(false)
+	if k.tamper {
+		t.Fatal("SetTamper(false) did not clear tamper")
+	}
+}
+
+func TestKeypadHandleKeyTamper(t *testing.T) {
+	for _, c := range []struct {
+		name       string
+		in         byte
+		wantTamper bool
+		wantKey    byte
+		wantAckKey bool
+	}{
+		{name: "tamper sentinel", in: 0x7F, wantTamper: true},
+		{name: "key with tamper flag", in: 0x43, wantTamper: true, wantKey: keypadKeys[0x3], wantAckKey: true},
+		{name: "plain key", in: 0x05, wantTamper: false, wantKey: keypadKeys[0x5], wantAckKey: true},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			k := NewKeypad("", "")
+			k.handleKeyTamper(c.in)
+
+			if k.tamper != c.wantTamper {
+				t.Errorf("tamper = %v, want %v", k.tamper, c.wantTamper)
+			}
+			if k.ackKey != c.wantAckKey {
+				t.Errorf("ackKey = %v, want %v", k.ackKey, c.wantAckKey)
+			}
+			if c.wantKey != 0 && k.lastKey != c.wantKey {
+				t.Errorf("lastKey = %q, want %q", k.lastKey, c.wantKey)
+			}
+		})
+	}
+}
+
+func TestKeypadSendScreenFlags(t *testing.T) {
+	k := NewKeypad("hello", "world")
+	port := &fakePort{}
+
+	if err := k.sendScreen(port); err != nil {
+		t.Fatalf("sendScreen: %s", err)
+	}
+	msg := port.last()
+	if got, want := msg[2], byte(0x81); got != want {
+		t.Errorf("first sendScreen flags = %#02x, want %#02x", got, want)
+	}
+
+	k.PressKey('5')
+	if err := k.sendScreen(port); err != nil {
+		t.Fatalf("sendScreen: %s", err)
+	}
+	msg = port.last()
+	if got, want := msg[2], byte(0x13); got != want {
+		t.Errorf("sendScreen flags after a pending key ack = %#02x, want %#02x", got, want)
+	}
+	if k.ackKey {
+		t.Error("sendScreen did not clear ackKey after reporting it")
+	}
+
+	// The key overlay sits in the last column of line 0 while the key
+	// press is still fresh (within 3s).
+	line0 := msg[5 : 5+16]
+	if line0[15] != '5' {
+		t.Errorf("line0 last byte = %q, want the pressed key '5'", line0[15])
+	}
+
+	k.SetTamper(true)
+	if err := k.sendScreen(port); err != nil {
+		t.Fatalf("sendScreen: %s", err)
+	}
+	msg = port.last()
+	line1 := msg[5+16+1 : 5+16+1+16]
+	if line1[15] != 'T' {
+		t.Errorf("line1 last byte = %q, want the tamper overlay 'T'", line1[15])
+	}
+}
+
+func TestPadLine(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		text    string
+		overlay bool
+		b       byte
+		want    []byte
+	}{
+		{name: "short, no overlay", text: "hi", overlay: false, b: 'X', want: append([]byte("hi"), bytes.Repeat([]byte{0x20}, 14)...)},
+		{name: "short, overlay", text: "hi", overlay: true, b: 'X', want: append(append([]byte("hi"), bytes.Repeat([]byte{0x20}, 13)...), 'X')},
+		{name: "truncated", text: "0123456789ABCDEFGHIJ", overlay: false, b: 'X', want: []byte("0123456789ABCDEF")},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			got := padLine(c.text, c.overlay, c.b)
+			if len(got) != 16 {
+				t.Fatalf("len = %d, want 16", len(got))
+			}
+			if !bytes.Equal(got, c.want) {
+				t.Errorf("padLine(%q, %v, %q) = % 02X, want % 02X", c.text, c.overlay, c.b, got, c.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
 package galaxy
 
 import (
-	"encoding/gob"
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -14,10 +15,13 @@ type Message struct {
 	Timestamp time.Time
 }
 
+// Recorder appends Messages to Dest using Format. Format defaults to
+// GobFormat if unset.
 type Recorder struct {
-	Dest io.Writer
+	Dest   io.Writer
+	Format Format
 
-	enc  *gob.Encoder
+	enc  MessageEncoder
 	once sync.Once
 }
 
@@ -28,14 +32,35 @@ func (r *Recorder) Receive(msg Message) error {
 
 func (r *Recorder) init() {
 	r.once.Do(func() {
-		r.enc = gob.NewEncoder(r.Dest)
+		format := r.Format
+		if format == nil {
+			format = GobFormat{}
+		}
+
+		r.enc = format.Encoder(r.Dest)
 	})
 }
 
-func ReadIn(out chan<- Message, r io.Reader) error {
+// ReadIn reads a capture from r, auto-detecting whether it was written as
+// gob, JSON Lines or pcap-ng, and sends each decoded Message to out. It
+// closes out once the capture is exhausted, or returns early if ctx is
+// cancelled - which also unblocks a send to out that a consumer has
+// stopped draining, so a cancelled ReadIn can't wedge its caller's
+// errgroup.Wait forever behind a full channel.
+func ReadIn(ctx context.Context, out chan<- Message, r io.Reader) error {
 	defer close(out)
 
-	dec := gob.NewDecoder(r)
+	br := bufio.NewReader(r)
+
+	format, err := detectFormat(br)
+	if errors.Is(err, errNoData) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("detecting capture format: %w", err)
+	}
+
+	dec := format.Decoder(br)
 	var msg Message
 
 	for {
@@ -47,6 +72,147 @@ func ReadIn(out chan<- Message, r io.Reader) error {
 			return fmt.Errorf("while decoding: %w", err)
 		}
 
-		out <- msg
+		select {
+		case out <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// followPollInterval is how long FollowIn waits before retrying a read
+// that found no complete record.
+const followPollInterval = 200 * time.Millisecond
+
+// FollowIn behaves like ReadIn, except that when it reaches the end of r
+// - including mid-record, if a concurrent writer is still flushing a
+// record's bytes - it waits and retries rather than returning, so it can
+// tail a capture file that is still being written. It returns only when
+// ctx is cancelled or decoding hits a non-EOF error.
+//
+// r must also implement io.Seeker: a concurrent writer can leave a
+// record torn mid-flush, and a decoder that has partially consumed a
+// torn record may desync, so FollowIn recovers by rewinding to the
+// start of the capture and redecoding, discarding the messages already
+// delivered, rather than resuming the same decoder in place.
+func FollowIn(ctx context.Context, out chan<- Message, r io.Reader) error {
+	defer close(out)
+
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return errors.New("follow requires a seekable capture")
+	}
+
+	start, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("locating capture offset: %w", err)
+	}
+
+	br := bufio.NewReader(r)
+
+	format, err := detectFormat(br)
+	for errors.Is(err, errNoData) {
+		// The capture file exists but a concurrent sniff hasn't flushed
+		// its first bytes yet; wait rather than locking in a guessed
+		// format from an empty peek.
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followPollInterval):
+		}
+
+		format, err = detectFormat(br)
+	}
+	if err != nil {
+		return fmt.Errorf("detecting capture format: %w", err)
+	}
+
+	dec := format.Decoder(br)
+	var msg Message
+	delivered := 0
+
+	for {
+		err := dec.Decode(&msg)
+		switch {
+		case err == nil:
+			delivered++
+			select {
+			case out <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+		case errors.Is(err, io.EOF):
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(followPollInterval):
+			}
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(followPollInterval):
+			}
+
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return fmt.Errorf("rewinding after a torn record: %w", err)
+			}
+
+			br = bufio.NewReader(r)
+			dec = format.Decoder(br)
+			if err := skipMessages(dec, delivered); err != nil {
+				return fmt.Errorf("replaying capture after a torn record: %w", err)
+			}
+		default:
+			return fmt.Errorf("while decoding: %w", err)
+		}
+	}
+}
+
+// skipMessages decodes and discards the first n messages from dec, so a
+// decoder reopened after a torn record can resume where a previous one,
+// now possibly desynced, left off.
+func skipMessages(dec MessageDecoder, n int) error {
+	var msg Message
+	for i := 0; i < n; i++ {
+		if err := dec.Decode(&msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errNoData means detectFormat had no bytes at all to sniff, which
+// FollowIn treats as "not written yet" rather than a genuinely empty,
+// closed capture.
+var errNoData = errors.New("no capture data buffered yet")
+
+// detectFormat sniffs the leading bytes of br, without consuming them, to
+// identify which Format a capture was written with.
+func detectFormat(br *bufio.Reader) (Format, error) {
+	head, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	if len(head) == 0 {
+		return nil, errNoData
+	}
+
+	if len(head) == 4 && head[0] == 0x0A && head[1] == 0x0D && head[2] == 0x0D && head[3] == 0x0A {
+		return PCAPNGFormat{}, nil
 	}
+
+	for _, b := range head {
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return JSONLinesFormat{}, nil
+		default:
+			return GobFormat{}, nil
+		}
+	}
+
+	return GobFormat{}, nil
 }